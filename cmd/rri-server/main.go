@@ -0,0 +1,169 @@
+// Command rri-server runs pkg/rriservice as a long-running gRPC (and REST,
+// via grpc-gateway) daemon in front of a pool of authenticated RRI
+// connections, so internal tools can share sessions instead of each
+// spawning the rri-client CLI.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/sbreitf1/go-console"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/DENICeG/go-rriclient/pkg/rriservice"
+	"github.com/DENICeG/go-rriclient/pkg/rriservice/rriservicepb"
+)
+
+var (
+	app               = kingpin.New("rri-server", "gRPC/REST gateway in front of a pool of authenticated RRI connections")
+	argConfig         = app.Flag("config", "Path to the JSON environment pool configuration").Short('c').Required().String()
+	argGRPCAddress    = app.Flag("grpc-address", "Address to serve gRPC on").Default(":8443").String()
+	argGatewayAddress = app.Flag("gateway-address", "Address to serve the REST gateway on").Default(":8080").String()
+	argServerCert     = app.Flag("server-cert", "Server TLS certificate").Required().String()
+	argServerKey      = app.Flag("server-key", "Server TLS key").Required().String()
+	argClientCA       = app.Flag("client-ca", "CA bundle used to verify caller (mTLS client) certificates").Required().String()
+	argServerCA       = app.Flag("server-ca", "CA bundle that signed --server-cert, used by the REST gateway to verify the in-process gRPC server it dials").Required().String()
+	argMaxConcurrent  = app.Flag("max-concurrent-per-caller", "Maximum in-flight queries a single session may have at once").Default("4").Int64()
+)
+
+// poolConfig is the on-disk shape of --config: one entry per RRI environment
+// the pool maintains authenticated connections for.
+type poolConfig struct {
+	Environments []rriservice.EnvironmentConfig `json:"environments"`
+}
+
+func main() {
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	if err := run(); err != nil {
+		console.Printlnf("FATAL: %s", err.Error())
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	data, err := ioutil.ReadFile(*argConfig)
+	if err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+
+	var cfg poolConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("parse config: %w", err)
+	}
+
+	pool := rriservice.NewPool(cfg.Environments)
+	defer pool.Close()
+
+	server := rriservice.NewServer(pool, *argMaxConcurrent)
+
+	tlsConfig, err := serverTLSConfig(*argServerCert, *argServerKey, *argClientCA)
+	if err != nil {
+		return fmt.Errorf("build tls config: %w", err)
+	}
+
+	gatewayDialTLSConfig, err := gatewayDialTLSConfig(*argServerCert, *argServerKey, *argServerCA)
+	if err != nil {
+		return fmt.Errorf("build gateway dial tls config: %w", err)
+	}
+
+	lis, err := net.Listen("tcp", *argGRPCAddress)
+	if err != nil {
+		return fmt.Errorf("listen on %q: %w", *argGRPCAddress, err)
+	}
+
+	grpcServer := grpc.NewServer(grpc.Creds(credentials.NewTLS(tlsConfig)))
+	rriservicepb.RegisterRRIServiceServer(grpcServer, server)
+
+	go func() {
+		console.Printlnf("serving gRPC on %s", *argGRPCAddress)
+		if err := grpcServer.Serve(lis); err != nil {
+			console.Printlnf("FATAL: grpc server: %s", err.Error())
+			os.Exit(1)
+		}
+	}()
+
+	return serveGateway(*argGatewayAddress, *argGRPCAddress, tlsConfig, gatewayDialTLSConfig)
+}
+
+func serverTLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load server cert/key: %w", err)
+	}
+
+	caData, err := ioutil.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("read client CA: %w", err)
+	}
+
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caData) {
+		return nil, fmt.Errorf("no certificates found in %q", clientCAFile)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    caPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}, nil
+}
+
+// gatewayDialTLSConfig builds the client-side TLS config the REST gateway
+// uses to dial the in-process gRPC server. It presents the same keypair as
+// its own client certificate (the gRPC server requires one) and verifies
+// the gRPC server's certificate against the CA that signed --server-cert,
+// rather than the system trust store.
+func gatewayDialTLSConfig(certFile, keyFile, serverCAFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load gateway dial cert/key: %w", err)
+	}
+
+	caData, err := ioutil.ReadFile(serverCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("read server CA: %w", err)
+	}
+
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caData) {
+		return nil, fmt.Errorf("no certificates found in %q", serverCAFile)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      caPool,
+	}, nil
+}
+
+func serveGateway(gatewayAddress, grpcAddress string, tlsConfig, dialTLSConfig *tls.Config) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mux := rriservicepb.NewServeMux()
+
+	dialCreds := credentials.NewTLS(dialTLSConfig.Clone())
+	if err := rriservicepb.RegisterRRIServiceHandlerFromEndpoint(ctx, mux, grpcAddress, []grpc.DialOption{grpc.WithTransportCredentials(dialCreds)}); err != nil {
+		return fmt.Errorf("register gateway handler: %w", err)
+	}
+
+	gatewayServer := &http.Server{
+		Addr:      gatewayAddress,
+		Handler:   mux,
+		TLSConfig: tlsConfig,
+	}
+
+	console.Printlnf("serving REST gateway on %s", gatewayAddress)
+	return gatewayServer.ListenAndServeTLS("", "")
+}
@@ -8,6 +8,9 @@ import (
 
 	"github.com/DENICeG/go-rriclient/internal/env"
 	"github.com/DENICeG/go-rriclient/pkg/rri"
+	"github.com/DENICeG/go-rriclient/pkg/rri/creds"
+	"github.com/DENICeG/go-rriclient/pkg/rri/creds/vaultplugin"
+	"github.com/DENICeG/go-rriclient/pkg/rri/transcript"
 
 	"github.com/sbreitf1/go-console"
 	"gopkg.in/alecthomas/kingpin.v2"
@@ -19,13 +22,35 @@ var (
 )
 
 var (
-	app            = kingpin.New("rri-client", "Client application for RRI")
-	argAddress     = app.Arg("address", "Address and port like host:1234 of the RRI host").String()
-	argFile        = app.Flag("file", "Input file containing RRI requests separated by a '=-=' line").Short('f').String()
-	argVerbose     = app.Flag("verbose", "Print all sent and received requests").Short('v').Bool()
-	argUser        = app.Flag("user", "RRI user to use for login").Short('u').String()
-	argPassword    = app.Flag("pass", "RRI password to use for login. Will be asked for if only user is set").Short('p').String()
-	argEnvironment = app.Flag("env", "Named environment to use or create").Short('e').String()
+	app             = kingpin.New("rri-client", "Client application for RRI")
+	argAddress      = app.Arg("address", "Address and port like host:1234 of the RRI host").String()
+	argFile         = app.Flag("file", "Input file containing RRI requests separated by a '=-=' line").Short('f').String()
+	argVerbose      = app.Flag("verbose", "Print all sent and received requests").Short('v').Bool()
+	argUser         = app.Flag("user", "RRI user to use for login").Short('u').String()
+	argPassword     = app.Flag("pass", "RRI password to use for login. Will be asked for if only user is set").Short('p').String()
+	argEnvironment  = app.Flag("env", "Named environment to use or create").Short('e').String()
+	argCredProvider = app.Flag("cred-provider", "Credential provider to resolve the environment's user/password from: file or vault").Default("file").String()
+	argVaultMount   = app.Flag("vault-mount", "Vault KV-v2 mount point to read RRI credentials from when --cred-provider=vault").Default(creds.DefaultVaultMount).String()
+	argCensorConfig = app.Flag("censor-config", "Path to a JSON file configuring which fields are redacted from printed and recorded messages").String()
+	argRecord       = app.Flag("record", "Record a JSON-lines transcript of this session to the given file").String()
+
+	cmdVault              = app.Command("vault", "Credential integrations for HashiCorp Vault")
+	cmdVaultServer        = cmdVault.Command("server", "Run as a Vault plugin secrets engine handing out ephemeral RRI sessions")
+	argVaultServerAddress = cmdVaultServer.Flag("rri-address", "RRI host to authenticate ephemeral sessions against").Short('a').Required().String()
+
+	cmdReplay             = app.Command("replay", "Replay a recorded transcript against a fresh RRI server")
+	argReplayFile         = cmdReplay.Arg("file", "Transcript file to replay").Required().String()
+	argReplayAddress      = cmdReplay.Flag("address", "RRI host to replay against").Short('a').Required().String()
+	argReplayUser         = cmdReplay.Flag("user", "RRI user to use for login").Short('u').String()
+	argReplayPassword     = cmdReplay.Flag("pass", "RRI password to use for login").Short('p').String()
+	argReplayValues       = cmdReplay.Flag("values", "Path to a JSON file of ${name} substitution values").String()
+	argReplayStopOnDiff   = cmdReplay.Flag("stop-on-diff", "Stop replay at the first response that diverges from the recording").Bool()
+	argReplayCensorConfig = cmdReplay.Flag("censor-config", "Path to a JSON file configuring which fields are redacted before comparing against the recording; must match the config used to record it").String()
+
+	cmdTranscript     = app.Command("transcript", "Transcript file tooling")
+	cmdTranscriptDiff = cmdTranscript.Command("diff", "Diff two recorded transcripts to see which responses changed")
+	argDiffFileA      = cmdTranscriptDiff.Arg("a", "First transcript file").Required().String()
+	argDiffFileB      = cmdTranscriptDiff.Arg("b", "Second transcript file").Required().String()
 )
 
 type environment struct {
@@ -39,9 +64,33 @@ func (e environment) HasCredentials() bool {
 }
 
 func main() {
-	kingpin.MustParse(app.Parse(os.Args[1:]))
+	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
+	case cmdVaultServer.FullCommand():
+		if err := vaultplugin.Serve(*argVaultServerAddress); err != nil {
+			console.Printlnf("FATAL: %s", err.Error())
+			os.Exit(1)
+		}
+		return
+	case cmdReplay.FullCommand():
+		if err := runReplay(); err != nil {
+			console.Printlnf("FATAL: %s", err.Error())
+			os.Exit(1)
+		}
+		return
+	case cmdTranscriptDiff.FullCommand():
+		if err := runTranscriptDiff(); err != nil {
+			console.Printlnf("FATAL: %s", err.Error())
+			os.Exit(1)
+		}
+		return
+	}
 
 	if err := func() error {
+		censor, err := loadCensor(*argCensorConfig)
+		if err != nil {
+			return err
+		}
+
 		env, err := retrieveEnvironment()
 		if err != nil {
 			return err
@@ -56,8 +105,21 @@ func main() {
 			return err
 		}
 		defer client.Close()
+
+		var printers []func(string, string)
 		if *argVerbose {
-			client.RawQueryPrinter = rawQueryPrinter
+			printers = append(printers, newRawQueryPrinter(censor))
+		}
+		if len(*argRecord) > 0 {
+			recorder, err := transcript.NewRecorder(*argRecord, env.Address, censor)
+			if err != nil {
+				return err
+			}
+			defer recorder.Close()
+			printers = append(printers, recorder.RawQueryPrinter())
+		}
+		if len(printers) > 0 {
+			client.RawQueryPrinter = combineRawQueryPrinters(printers...)
 		}
 
 		if env.HasCredentials() {
@@ -78,7 +140,7 @@ func main() {
 			}
 
 			for _, query := range queries {
-				console.Println("Exec query", query)
+				console.Println("Exec query", censor.Apply(fmt.Sprint(query)))
 				response, err := client.SendQuery(query)
 				if err != nil {
 					return err
@@ -100,45 +162,102 @@ func main() {
 	}
 }
 
+// retrieveEnvironment resolves the address and credentials to connect with.
+// Credentials are always resolved through a creds.CredentialProvider chosen
+// by --cred-provider, so --cred-provider=file and --cred-provider=vault are
+// handled identically once a provider exists. Only the "file" provider ever
+// touches the local jcrypt-encrypted environment file; --cred-provider=vault
+// never runs the interactive environment flow or writes anything to disk.
 func retrieveEnvironment() (environment, error) {
-	envReader, err := env.NewReader(".rri-client")
-	if err != nil {
-		return environment{}, err
-	}
-	envReader.EnterEnvHandler = enterEnvironment
-	envReader.GetEnvFileTitle = getEnvTitle
+	var e environment
 
-	var env environment
-	if len(*argEnvironment) > 0 {
-		err = envReader.CreateOrReadEnvironment(*argEnvironment, &env)
-	} else if len(*argAddress) == 0 {
-		err = envReader.SelectEnvironment(&env)
-	}
-	if err != nil {
-		return environment{}, err
+	if *argCredProvider == "" || *argCredProvider == "file" {
+		fileEnv, err := retrieveFileEnvironment()
+		if err != nil {
+			return environment{}, err
+		}
+		e = fileEnv
+	} else {
+		if len(*argEnvironment) == 0 {
+			return environment{}, fmt.Errorf("--cred-provider=%s requires --env to select which secret to read", *argCredProvider)
+		}
+		if len(*argAddress) == 0 {
+			return environment{}, fmt.Errorf("--cred-provider=%s requires --address, since it never reads the local environment file", *argCredProvider)
+		}
 	}
 
 	if len(*argAddress) > 0 {
-		env.Address = *argAddress
+		e.Address = *argAddress
 	}
 	if len(*argUser) > 0 {
-		env.User = *argUser
+		e.User = *argUser
 	}
 	if len(*argPassword) > 0 {
-		env.Password = *argPassword
+		e.Password = *argPassword
 	}
 
-	if len(env.User) > 0 && len(env.Password) == 0 {
+	// The file provider was already consulted via retrieveFileEnvironment
+	// above; re-resolving it here would just re-decrypt the same file and,
+	// worse, clobber the --user/--pass overrides applied a moment ago. Only
+	// non-file providers need a CredentialProvider lookup at this point, and
+	// only to fill in whichever of --user/--pass the CLI didn't already
+	// supply; a field the user did supply is never clobbered.
+	if len(*argEnvironment) > 0 && *argCredProvider != "" && *argCredProvider != "file" &&
+		(len(e.User) == 0 || len(e.Password) == 0) {
+		provider, err := creds.NewProvider(*argCredProvider, *argVaultMount)
+		if err != nil {
+			return environment{}, err
+		}
+
+		providerUser, providerPassword, err := provider.GetCredentials(*argEnvironment)
+		if err != nil {
+			return environment{}, err
+		}
+		if len(e.User) == 0 {
+			e.User = providerUser
+		}
+		if len(e.Password) == 0 {
+			e.Password = providerPassword
+		}
+	}
+
+	if len(e.User) > 0 && len(e.Password) == 0 {
 		var err error
-		console.Printlnf("Please enter RRI password for user %q", env.User)
+		console.Printlnf("Please enter RRI password for user %q", e.User)
 		console.Print("> ")
-		env.Password, err = console.ReadPassword()
+		e.Password, err = console.ReadPassword()
 		if err != nil {
 			return environment{}, err
 		}
 	}
 
-	return env, nil
+	return e, nil
+}
+
+// retrieveFileEnvironment reads (creating interactively if necessary) the
+// local jcrypt-encrypted environment file for --cred-provider=file. The
+// caller uses its credentials directly; unlike the other providers, the file
+// case never goes through creds.NewProvider, since this function already is
+// the file-backed resolution step.
+func retrieveFileEnvironment() (environment, error) {
+	envReader, err := env.NewReader(".rri-client")
+	if err != nil {
+		return environment{}, err
+	}
+	envReader.EnterEnvHandler = enterEnvironment
+	envReader.GetEnvFileTitle = getEnvTitle
+
+	var e environment
+	if len(*argEnvironment) > 0 {
+		err = envReader.CreateOrReadEnvironment(*argEnvironment, &e)
+	} else if len(*argAddress) == 0 {
+		err = envReader.SelectEnvironment(&e)
+	}
+	if err != nil {
+		return environment{}, err
+	}
+
+	return e, nil
 }
 
 func enterEnvironment(envName string, env interface{}) error {
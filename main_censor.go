@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/DENICeG/go-rriclient/pkg/rri"
+	"github.com/sbreitf1/go-console"
+)
+
+// loadCensor returns the censor to apply to printed and recorded messages.
+// With no --censor-config given, it falls back to rri's default, which only
+// redacts the password field.
+func loadCensor(path string) (*rri.Censor, error) {
+	if len(path) == 0 {
+		return rri.NewDefaultCensor(), nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read censor config %q: %w", path, err)
+	}
+
+	var censor rri.Censor
+	if err := json.Unmarshal(data, &censor); err != nil {
+		return nil, fmt.Errorf("parse censor config %q: %w", path, err)
+	}
+
+	return &censor, nil
+}
+
+// newRawQueryPrinter returns a rri.Client.RawQueryPrinter that censors every
+// sent and received message with censor before printing it.
+func newRawQueryPrinter(censor *rri.Censor) func(string, string) {
+	return func(kind, message string) {
+		console.Printlnf("%s: %s", kind, censor.Apply(message))
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/DENICeG/go-rriclient/pkg/rri"
+	"github.com/DENICeG/go-rriclient/pkg/rri/transcript"
+	"github.com/sbreitf1/go-console"
+)
+
+// combineRawQueryPrinters returns a single rri.Client.RawQueryPrinter hook
+// that forwards every call to each of fns in order.
+func combineRawQueryPrinters(fns ...func(string, string)) func(string, string) {
+	return func(kind, message string) {
+		for _, fn := range fns {
+			fn(kind, message)
+		}
+	}
+}
+
+// runReplay implements the "rri-client replay <file>" command: it resends
+// every query of a recorded transcript against a fresh RRI server and
+// reports any response that diverges from the recording.
+func runReplay() error {
+	entries, err := transcript.Read(*argReplayFile)
+	if err != nil {
+		return err
+	}
+
+	values, err := loadReplayValues(*argReplayValues)
+	if err != nil {
+		return err
+	}
+
+	censor, err := loadCensor(*argReplayCensorConfig)
+	if err != nil {
+		return err
+	}
+
+	client, err := rri.NewClient(*argReplayAddress)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if len(*argReplayUser) > 0 {
+		if err := client.Login(*argReplayUser, *argReplayPassword); err != nil {
+			return err
+		}
+	}
+
+	diffs, err := transcript.Replay(entries, client, transcript.ReplayOptions{
+		Values:     values,
+		StopOnDiff: *argReplayStopOnDiff,
+		Censor:     censor,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(diffs) == 0 {
+		console.Println("replay matched the recorded transcript exactly")
+		return nil
+	}
+
+	for _, d := range diffs {
+		console.Println(d.String())
+	}
+	return fmt.Errorf("replay diverged from the recorded transcript in %d place(s)", len(diffs))
+}
+
+func loadReplayValues(path string) (map[string]string, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read values file %q: %w", path, err)
+	}
+
+	var values map[string]string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("parse values file %q: %w", path, err)
+	}
+	return values, nil
+}
+
+// runTranscriptDiff implements "rri-client transcript diff <a> <b>".
+func runTranscriptDiff() error {
+	a, err := transcript.Read(*argDiffFileA)
+	if err != nil {
+		return err
+	}
+	b, err := transcript.Read(*argDiffFileB)
+	if err != nil {
+		return err
+	}
+
+	diffs := transcript.DiffTranscripts(a, b)
+	if len(diffs) == 0 {
+		console.Println("transcripts are identical")
+		return nil
+	}
+
+	for _, d := range diffs {
+		console.Println(d.String())
+	}
+	return fmt.Errorf("transcripts diverged in %d place(s)", len(diffs))
+}
@@ -0,0 +1,136 @@
+package rri
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// fullMask replaces a censored value in full, regardless of its original length.
+const fullMask = "******"
+
+// MatchMode selects how a CensorField's Field is matched against a message's
+// field name.
+type MatchMode int
+
+const (
+	// MatchExact matches the field name exactly.
+	MatchExact MatchMode = iota
+	// MatchPrefix matches any field name starting with Field.
+	MatchPrefix
+	// MatchRegex matches the field name against the regular expression in Field.
+	MatchRegex
+)
+
+// ReplaceMode selects how a matched field's value is obscured.
+type ReplaceMode int
+
+const (
+	// ReplaceFull replaces the entire value with fullMask.
+	ReplaceFull ReplaceMode = iota
+	// ReplaceKeepLastN keeps the last KeepLastN characters of the value and masks the rest.
+	ReplaceKeepLastN
+	// ReplaceHash replaces the value with a short hex-encoded SHA-256 hash of it.
+	ReplaceHash
+)
+
+// CensorField describes one field-name matcher and the strategy used to
+// obscure its value.
+type CensorField struct {
+	Field   string      `json:"field"`
+	Mode    MatchMode   `json:"mode"`
+	Replace ReplaceMode `json:"replace"`
+	// KeepLastN is only used when Replace is ReplaceKeepLastN.
+	KeepLastN int `json:"keepLastN,omitempty"`
+
+	regexOnce sync.Once
+	regex     *regexp.Regexp
+}
+
+func (f *CensorField) matches(name string) bool {
+	switch f.Mode {
+	case MatchPrefix:
+		return strings.HasPrefix(name, f.Field)
+	case MatchRegex:
+		f.regexOnce.Do(func() {
+			f.regex = regexp.MustCompile(f.Field)
+		})
+		return f.regex.MatchString(name)
+	default:
+		return name == f.Field
+	}
+}
+
+func (f *CensorField) obscure(value string) string {
+	switch f.Replace {
+	case ReplaceKeepLastN:
+		if len(value) <= f.KeepLastN {
+			return fullMask
+		}
+		return fullMask + value[len(value)-f.KeepLastN:]
+	case ReplaceHash:
+		sum := sha256.Sum256([]byte(value))
+		return "sha256:" + hex.EncodeToString(sum[:])[:12]
+	default:
+		return fullMask
+	}
+}
+
+// Censor redacts configured fields from raw RRI messages before they reach
+// logs, traces, or transcripts.
+type Censor struct {
+	Fields []CensorField `json:"fields"`
+}
+
+// NewDefaultCensor returns the censor CensorRawMessage has always applied: a
+// full mask on the "password" field.
+func NewDefaultCensor() *Censor {
+	return &Censor{
+		Fields: []CensorField{
+			{Field: "password", Mode: MatchExact, Replace: ReplaceFull},
+		},
+	}
+}
+
+// Apply returns msg with every line whose field name matches one of the
+// censor's Fields obscured, preserving all other lines verbatim.
+func (c *Censor) Apply(msg string) string {
+	lines := strings.Split(msg, "\n")
+	for i, line := range lines {
+		key, value, ok := splitField(line)
+		if !ok || len(value) == 0 {
+			continue
+		}
+
+		for fi := range c.Fields {
+			f := &c.Fields[fi]
+			if f.matches(key) {
+				lines[i] = key + ": " + f.obscure(value)
+				break
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// splitField splits a raw RRI message line of the form "key: value" into its
+// key and value. Lines without the "key: value" delimiter are not fields.
+func splitField(line string) (key, value string, ok bool) {
+	idx := strings.Index(line, ": ")
+	if idx < 0 {
+		return "", "", false
+	}
+	return line[:idx], line[idx+2:], true
+}
+
+var defaultCensor = NewDefaultCensor()
+
+// CensorRawMessage redacts the password field of a raw RRI message using the
+// default censor. It is kept for backward compatibility; new code should
+// build and apply a Censor directly so it can scrub additional fields such
+// as authinfo, token or secret.
+func CensorRawMessage(msg string) string {
+	return defaultCensor.Apply(msg)
+}
@@ -0,0 +1,64 @@
+package rri
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCensorApplyExactMatch(t *testing.T) {
+	c := NewDefaultCensor()
+	assert.Equal(t, "password: ******\naction: LOGIN", c.Apply("password: secret\naction: LOGIN"))
+}
+
+func TestCensorApplyPrefixMatch(t *testing.T) {
+	c := &Censor{Fields: []CensorField{{Field: "contact-", Mode: MatchPrefix, Replace: ReplaceFull}}}
+	assert.Equal(t, "contact-email: ******\ncontact-phone: ******\ndomain: denic.de",
+		c.Apply("contact-email: jane@example.com\ncontact-phone: +49123456\ndomain: denic.de"))
+}
+
+func TestCensorApplyRegexMatch(t *testing.T) {
+	c := &Censor{Fields: []CensorField{{Field: "^x-.*-token$", Mode: MatchRegex, Replace: ReplaceFull}}}
+	assert.Equal(t, "x-auth-token: ******\nx-other: kept", c.Apply("x-auth-token: abc123\nx-other: kept"))
+}
+
+func TestCensorApplyKeepLastN(t *testing.T) {
+	c := &Censor{Fields: []CensorField{{Field: "authinfo", Mode: MatchExact, Replace: ReplaceKeepLastN, KeepLastN: 4}}}
+	assert.Equal(t, "authinfo: ******4321", c.Apply("authinfo: 123454321"))
+}
+
+func TestCensorApplyHash(t *testing.T) {
+	c := &Censor{Fields: []CensorField{{Field: "token", Mode: MatchExact, Replace: ReplaceHash}}}
+	result := c.Apply("token: abc123")
+	assert.NotContains(t, result, "abc123")
+	assert.Contains(t, result, "sha256:")
+}
+
+func TestCensorApplyRegexMatchesRepeatedly(t *testing.T) {
+	// Exercises the same CensorField across many lines so a regression that
+	// re-ranges by value (and never caches the compiled regexp on the real
+	// slice element) would still be functionally correct here; the cache
+	// only affects performance, so this mainly guards the matching behavior
+	// itself stays correct across repeated use of the same field.
+	c := &Censor{Fields: []CensorField{{Field: "^secret-.*$", Mode: MatchRegex, Replace: ReplaceFull}}}
+	msg := "secret-one: a\nsecret-two: b\nsecret-three: c"
+	assert.Equal(t, "secret-one: ******\nsecret-two: ******\nsecret-three: ******", c.Apply(msg))
+}
+
+func TestCensorApplyRegexMatchConcurrentUse(t *testing.T) {
+	// A shared regex-mode Censor (e.g. pkg/rriservice applying the same
+	// *Censor to every pooled connection's traffic) must survive concurrent
+	// Apply calls without a data race on the lazily compiled regexp.
+	c := &Censor{Fields: []CensorField{{Field: "^secret-.*$", Mode: MatchRegex, Replace: ReplaceFull}}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, "secret-one: ******", c.Apply("secret-one: a"))
+		}()
+	}
+	wg.Wait()
+}
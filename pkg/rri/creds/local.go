@@ -0,0 +1,46 @@
+package creds
+
+import (
+	"fmt"
+
+	"github.com/DENICeG/go-rriclient/internal/env"
+)
+
+// localEnvironment mirrors the environment file layout main.go has always used,
+// including the jcrypt-encrypted password field.
+type localEnvironment struct {
+	Address  string `json:"address"`
+	User     string `json:"user"`
+	Password string `json:"pass" jcrypt:"aes"`
+}
+
+// LocalFileProvider resolves credentials from the encrypted local environment
+// files managed by internal/env. This is the historical, and still default,
+// behavior.
+type LocalFileProvider struct {
+	Dir string
+}
+
+// NewLocalFileProvider returns a LocalFileProvider reading environment files from dir.
+func NewLocalFileProvider(dir string) *LocalFileProvider {
+	return &LocalFileProvider{Dir: dir}
+}
+
+// GetCredentials implements CredentialProvider.
+func (p *LocalFileProvider) GetCredentials(envName string) (string, string, error) {
+	envReader, err := env.NewReader(p.Dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	var e localEnvironment
+	if err := envReader.CreateOrReadEnvironment(envName, &e); err != nil {
+		return "", "", err
+	}
+
+	if len(e.User) == 0 || len(e.Password) == 0 {
+		return "", "", fmt.Errorf("environment %q has no stored credentials", envName)
+	}
+
+	return e.User, e.Password, nil
+}
@@ -0,0 +1,27 @@
+// Package creds resolves RRI login credentials for a named environment from
+// pluggable backends, so they no longer have to live exclusively in a local
+// jcrypt-encrypted environment file.
+package creds
+
+import "fmt"
+
+// CredentialProvider resolves the RRI user and password to use for a named
+// environment. Implementations may read from disk, a secret store, or
+// anything else capable of answering the question.
+type CredentialProvider interface {
+	// GetCredentials returns the RRI user and password configured for envName.
+	GetCredentials(envName string) (user, pass string, err error)
+}
+
+// NewProvider resolves a CredentialProvider by name: "file" (the default,
+// backed by the local jcrypt-encrypted environment file) or "vault".
+func NewProvider(kind, vaultMount string) (CredentialProvider, error) {
+	switch kind {
+	case "", "file":
+		return NewLocalFileProvider(".rri-client"), nil
+	case "vault":
+		return NewVaultProvider(vaultMount)
+	default:
+		return nil, fmt.Errorf("unknown credential provider %q", kind)
+	}
+}
@@ -0,0 +1,25 @@
+package creds
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProviderFile(t *testing.T) {
+	provider, err := NewProvider("file", "")
+	require.NoError(t, err)
+	assert.IsType(t, &LocalFileProvider{}, provider)
+}
+
+func TestNewProviderDefaultsToFile(t *testing.T) {
+	provider, err := NewProvider("", "")
+	require.NoError(t, err)
+	assert.IsType(t, &LocalFileProvider{}, provider)
+}
+
+func TestNewProviderUnknown(t *testing.T) {
+	_, err := NewProvider("carrier-pigeon", "")
+	assert.Error(t, err)
+}
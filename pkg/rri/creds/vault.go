@@ -0,0 +1,94 @@
+package creds
+
+import (
+	"fmt"
+	"os"
+
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+// DefaultVaultMount is the KV-v2 mount point RRI secrets are expected under
+// when none is configured explicitly.
+const DefaultVaultMount = "secret"
+
+// VaultProvider resolves RRI credentials from a Vault KV-v2 secrets engine,
+// reading the "user" and "password" fields of secret/data/rri/<env>.
+type VaultProvider struct {
+	client *vaultapi.Client
+	mount  string
+}
+
+// NewVaultProvider builds a VaultProvider authenticated from the environment:
+// VAULT_ADDR plus either VAULT_TOKEN, or VAULT_ROLE_ID/VAULT_SECRET_ID for an
+// AppRole login. mount defaults to DefaultVaultMount when empty.
+func NewVaultProvider(mount string) (*VaultProvider, error) {
+	cfg := vaultapi.DefaultConfig()
+	if err := cfg.ReadEnvironment(); err != nil {
+		return nil, fmt.Errorf("read vault environment: %w", err)
+	}
+
+	client, err := vaultapi.NewClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create vault client: %w", err)
+	}
+
+	if token := os.Getenv("VAULT_TOKEN"); len(token) > 0 {
+		client.SetToken(token)
+	} else if err := loginAppRole(client); err != nil {
+		return nil, err
+	}
+
+	if len(mount) == 0 {
+		mount = DefaultVaultMount
+	}
+
+	return &VaultProvider{client: client, mount: mount}, nil
+}
+
+func loginAppRole(client *vaultapi.Client) error {
+	roleID := os.Getenv("VAULT_ROLE_ID")
+	secretID := os.Getenv("VAULT_SECRET_ID")
+	if len(roleID) == 0 || len(secretID) == 0 {
+		return fmt.Errorf("no VAULT_TOKEN and no VAULT_ROLE_ID/VAULT_SECRET_ID set")
+	}
+
+	secret, err := client.Logical().Write("auth/approle/login", map[string]interface{}{
+		"role_id":   roleID,
+		"secret_id": secretID,
+	})
+	if err != nil {
+		return fmt.Errorf("approle login: %w", err)
+	}
+	if secret == nil || secret.Auth == nil {
+		return fmt.Errorf("approle login returned no auth info")
+	}
+
+	client.SetToken(secret.Auth.ClientToken)
+	return nil
+}
+
+// GetCredentials implements CredentialProvider.
+func (p *VaultProvider) GetCredentials(envName string) (string, string, error) {
+	path := fmt.Sprintf("%s/data/rri/%s", p.mount, envName)
+
+	secret, err := p.client.Logical().Read(path)
+	if err != nil {
+		return "", "", fmt.Errorf("read %q from vault: %w", path, err)
+	}
+	if secret == nil || secret.Data == nil {
+		return "", "", fmt.Errorf("no secret found at %q", path)
+	}
+
+	data, ok := secret.Data["data"].(map[string]interface{})
+	if !ok {
+		return "", "", fmt.Errorf("secret at %q has no kv-v2 data section", path)
+	}
+
+	user, _ := data["user"].(string)
+	pass, _ := data["password"].(string)
+	if len(user) == 0 || len(pass) == 0 {
+		return "", "", fmt.Errorf("secret at %q is missing user/password fields", path)
+	}
+
+	return user, pass, nil
+}
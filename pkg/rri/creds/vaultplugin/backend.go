@@ -0,0 +1,259 @@
+// Package vaultplugin implements an RRI secrets engine that can be run as a
+// HashiCorp Vault plugin. Operators write the RRI credentials to rotate away
+// from disk once, to config/<env>; reading creds/<env> then performs a live
+// Client.Login with the stored credentials and hands back a leased,
+// renewable session token instead of the password itself, so callers never
+// need to possess the plaintext credentials to pull an ephemeral session.
+// The session token is only useful because it can then be used at
+// sessions/<session>/query to run raw RRI queries through the held
+// connection, without the caller ever seeing the underlying credentials.
+package vaultplugin
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/DENICeG/go-rriclient/pkg/rri"
+)
+
+// DefaultSessionTTL is how long an ephemeral RRI session handed out by
+// creds/<env> stays open before its lease expires.
+const DefaultSessionTTL = 15 * time.Minute
+
+// Config configures the RRI host ephemeral sessions are authenticated against.
+type Config struct {
+	Address string
+}
+
+// Backend is the RRI credential secrets engine. Reading creds/<env> logs in
+// to the configured RRI host with the credentials stored at config/<env> and
+// hands back a leased session token; the underlying rri.Client is kept open
+// server-side until the lease is renewed, revoked, or expires.
+type Backend struct {
+	*framework.Backend
+	config Config
+
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+// session pairs a held rri.Client with the mutex that serializes access to
+// it, since a single RRI TCP session cannot interleave requests and Vault
+// may call sessions/<id>/query concurrently.
+type session struct {
+	mu     sync.Mutex
+	client *rri.Client
+}
+
+// New builds the backend for the given config.
+func New(config Config) *Backend {
+	b := &Backend{
+		config:   config,
+		sessions: make(map[string]*session),
+	}
+
+	b.Backend = &framework.Backend{
+		Help:        "The rri secrets engine hands out ephemeral RRI sessions for a configured RRI host.",
+		BackendType: logical.TypeLogical,
+		Paths: []*framework.Path{
+			{
+				Pattern: "config/" + framework.GenericNameRegex("env"),
+				Fields: map[string]*framework.FieldSchema{
+					"env":      {Type: framework.TypeString, Description: "Name of the RRI environment to configure."},
+					"user":     {Type: framework.TypeString, Description: "RRI user the plugin logs in as on creds/<env> reads."},
+					"password": {Type: framework.TypeString, Description: "RRI password for user."},
+				},
+				Callbacks: map[logical.Operation]framework.OperationFunc{
+					logical.CreateOperation: b.handleWriteConfig,
+					logical.UpdateOperation: b.handleWriteConfig,
+					logical.ReadOperation:   b.handleReadConfig,
+					logical.DeleteOperation: b.handleDeleteConfig,
+				},
+			},
+			{
+				Pattern: "creds/" + framework.GenericNameRegex("env"),
+				Fields: map[string]*framework.FieldSchema{
+					"env": {Type: framework.TypeString, Description: "Name of the RRI environment to authenticate against."},
+				},
+				Callbacks: map[logical.Operation]framework.OperationFunc{
+					logical.ReadOperation: b.handleCreds,
+				},
+			},
+			{
+				Pattern: "sessions/" + framework.GenericNameRegex("session") + "/query",
+				Fields: map[string]*framework.FieldSchema{
+					"session":   {Type: framework.TypeString, Description: "Session id returned by creds/<env>."},
+					"raw_query": {Type: framework.TypeString, Description: "Single raw RRI query to run through the held session."},
+				},
+				Callbacks: map[logical.Operation]framework.OperationFunc{
+					logical.UpdateOperation: b.handleQuery,
+				},
+			},
+		},
+		Secrets: []*framework.Secret{
+			{
+				Type: "rri_session",
+				Fields: map[string]*framework.FieldSchema{
+					"env":     {Type: framework.TypeString},
+					"user":    {Type: framework.TypeString},
+					"session": {Type: framework.TypeString},
+				},
+				Renew:  b.handleRenew,
+				Revoke: b.handleRevoke,
+			},
+		},
+	}
+
+	return b
+}
+
+func newSessionID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// handleCreds logs in to the configured RRI host with the credentials stored
+// at config/<env> and returns a leased, renewable session token. The
+// underlying connection is kept open until the lease is revoked or expires,
+// at which point handleRevoke closes it.
+func (b *Backend) handleCreds(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	envName := data.Get("env").(string)
+	if len(envName) == 0 {
+		return nil, fmt.Errorf("env is required")
+	}
+
+	cfg, err := b.readConfig(ctx, req.Storage, envName)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return nil, fmt.Errorf("no credentials configured for environment %q, write config/%s first", envName, envName)
+	}
+
+	client, err := rri.NewClient(b.config.Address)
+	if err != nil {
+		return nil, fmt.Errorf("connect to RRI host %q: %w", b.config.Address, err)
+	}
+
+	if err := client.Login(cfg.User, cfg.Password); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("login to RRI as %q: %w", cfg.User, err)
+	}
+
+	sessionID, err := newSessionID()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	b.mu.Lock()
+	b.sessions[sessionID] = &session{client: client}
+	b.mu.Unlock()
+
+	return &logical.Response{
+		Secret: &logical.Secret{
+			InternalData: map[string]interface{}{
+				"env":     envName,
+				"user":    cfg.User,
+				"session": sessionID,
+			},
+			LeaseOptions: logical.LeaseOptions{
+				TTL:       DefaultSessionTTL,
+				Renewable: true,
+			},
+		},
+		Data: map[string]interface{}{
+			"env":     envName,
+			"user":    cfg.User,
+			"session": sessionID,
+		},
+	}, nil
+}
+
+// handleRenew extends the lease of a session returned by handleCreds without
+// touching the underlying RRI connection.
+func (b *Backend) handleRenew(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	resp := &logical.Response{Secret: req.Secret}
+	resp.Secret.TTL = DefaultSessionTTL
+	return resp, nil
+}
+
+// handleRevoke closes the rri.Client behind an expired or explicitly revoked
+// session.
+func (b *Backend) handleRevoke(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	sessionID, ok := req.Secret.InternalData["session"].(string)
+	if !ok {
+		return nil, fmt.Errorf("secret is missing its session id")
+	}
+
+	b.mu.Lock()
+	sess, ok := b.sessions[sessionID]
+	delete(b.sessions, sessionID)
+	b.mu.Unlock()
+
+	if ok {
+		sess.mu.Lock()
+		sess.client.Close()
+		sess.mu.Unlock()
+	}
+
+	return nil, nil
+}
+
+// handleQuery runs a single raw RRI query through the rri.Client held open
+// for session (as returned by handleCreds) and returns its response. This is
+// the only thing a session id is actually good for; without it, creds/<env>
+// would just log in and immediately leave the connection idle until revoke.
+func (b *Backend) handleQuery(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	sessionID := data.Get("session").(string)
+	rawQuery := data.Get("raw_query").(string)
+	if len(rawQuery) == 0 {
+		return nil, fmt.Errorf("raw_query is required")
+	}
+
+	b.mu.Lock()
+	sess, ok := b.sessions[sessionID]
+	b.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no open session %q, it may have expired or been revoked", sessionID)
+	}
+
+	queries, err := rri.ParseQueries(rawQuery)
+	if err != nil {
+		return nil, err
+	}
+	if len(queries) != 1 {
+		return nil, fmt.Errorf("expected exactly one query, got %d", len(queries))
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	response, err := sess.client.SendQuery(queries[0])
+	if err != nil {
+		return nil, fmt.Errorf("send query through session %q: %w", sessionID, err)
+	}
+
+	respData := map[string]interface{}{
+		"successful": false,
+	}
+	if response != nil {
+		respData["successful"] = response.IsSuccessful()
+		respData["raw_response"] = rri.CensorRawMessage(response.String())
+		if !response.IsSuccessful() {
+			respData["error_message"] = response.ErrorMsg()
+		}
+	}
+
+	return &logical.Response{Data: respData}, nil
+}
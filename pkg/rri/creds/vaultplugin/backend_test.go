@@ -0,0 +1,69 @@
+package vaultplugin
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSessionIDIsUniqueAndHex(t *testing.T) {
+	a, err := newSessionID()
+	require.NoError(t, err)
+	b, err := newSessionID()
+	require.NoError(t, err)
+
+	assert.NotEqual(t, a, b)
+	_, err = hex.DecodeString(a)
+	assert.NoError(t, err)
+}
+
+func TestHandleCredsRequiresConfig(t *testing.T) {
+	b := New(Config{Address: "rri.example.org:51131"})
+	storage := &logical.InmemStorage{}
+
+	_, err := b.handleCreds(context.Background(), &logical.Request{Storage: storage}, &framework.FieldData{
+		Raw:    map[string]interface{}{"env": "ote"},
+		Schema: map[string]*framework.FieldSchema{"env": {Type: framework.TypeString}},
+	})
+	assert.Error(t, err)
+}
+
+func TestHandleQueryUnknownSession(t *testing.T) {
+	b := New(Config{Address: "rri.example.org:51131"})
+
+	_, err := b.handleQuery(context.Background(), &logical.Request{}, &framework.FieldData{
+		Raw: map[string]interface{}{"session": "does-not-exist", "raw_query": "action: INFO\ndomain: denic.de"},
+		Schema: map[string]*framework.FieldSchema{
+			"session":   {Type: framework.TypeString},
+			"raw_query": {Type: framework.TypeString},
+		},
+	})
+	assert.Error(t, err)
+}
+
+func TestHandleQueryRequiresRawQuery(t *testing.T) {
+	b := New(Config{Address: "rri.example.org:51131"})
+
+	_, err := b.handleQuery(context.Background(), &logical.Request{}, &framework.FieldData{
+		Raw: map[string]interface{}{"session": "does-not-exist", "raw_query": ""},
+		Schema: map[string]*framework.FieldSchema{
+			"session":   {Type: framework.TypeString},
+			"raw_query": {Type: framework.TypeString},
+		},
+	})
+	assert.Error(t, err)
+}
+
+func TestHandleRevokeUnknownSessionIsNoop(t *testing.T) {
+	b := New(Config{Address: "rri.example.org:51131"})
+
+	_, err := b.handleRevoke(context.Background(), &logical.Request{
+		Secret: &logical.Secret{InternalData: map[string]interface{}{"session": "does-not-exist"}},
+	}, &framework.FieldData{})
+	assert.NoError(t, err)
+}
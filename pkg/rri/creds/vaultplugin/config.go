@@ -0,0 +1,85 @@
+package vaultplugin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// envConfig is the per-environment configuration stored server-side at
+// config/<env>: the RRI credentials creds/<env> logs in with on the
+// operator's behalf.
+type envConfig struct {
+	User     string `json:"user"`
+	Password string `json:"password"`
+}
+
+func configStorageKey(envName string) string {
+	return "config/" + envName
+}
+
+func (b *Backend) readConfig(ctx context.Context, storage logical.Storage, envName string) (*envConfig, error) {
+	entry, err := storage.Get(ctx, configStorageKey(envName))
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var cfg envConfig
+	if err := entry.DecodeJSON(&cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// handleWriteConfig stores the RRI credentials creds/<env> will log in with.
+func (b *Backend) handleWriteConfig(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	envName := data.Get("env").(string)
+	cfg := envConfig{
+		User:     data.Get("user").(string),
+		Password: data.Get("password").(string),
+	}
+	if len(envName) == 0 || len(cfg.User) == 0 || len(cfg.Password) == 0 {
+		return nil, fmt.Errorf("env, user and password are required")
+	}
+
+	entry, err := logical.StorageEntryJSON(configStorageKey(envName), cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Storage.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// handleReadConfig returns the configured user for envName, never the
+// password.
+func (b *Backend) handleReadConfig(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	envName := data.Get("env").(string)
+
+	cfg, err := b.readConfig(ctx, req.Storage, envName)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return nil, nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"env":  envName,
+			"user": cfg.User,
+		},
+	}, nil
+}
+
+func (b *Backend) handleDeleteConfig(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	envName := data.Get("env").(string)
+	return nil, req.Storage.Delete(ctx, configStorageKey(envName))
+}
@@ -0,0 +1,86 @@
+package vaultplugin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var configFields = map[string]*framework.FieldSchema{
+	"env":      {Type: framework.TypeString},
+	"user":     {Type: framework.TypeString},
+	"password": {Type: framework.TypeString},
+}
+
+func TestHandleWriteConfigThenReadConfig(t *testing.T) {
+	b := New(Config{Address: "rri.example.org:51131"})
+	storage := &logical.InmemStorage{}
+	ctx := context.Background()
+
+	_, err := b.handleWriteConfig(ctx, &logical.Request{Storage: storage}, &framework.FieldData{
+		Raw:    map[string]interface{}{"env": "ote", "user": "DENIC-1000011-RRI", "password": "secret"},
+		Schema: configFields,
+	})
+	require.NoError(t, err)
+
+	resp, err := b.handleReadConfig(ctx, &logical.Request{Storage: storage}, &framework.FieldData{
+		Raw:    map[string]interface{}{"env": "ote"},
+		Schema: configFields,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, "DENIC-1000011-RRI", resp.Data["user"])
+	assert.NotContains(t, resp.Data, "password")
+}
+
+func TestHandleReadConfigMissingReturnsNil(t *testing.T) {
+	b := New(Config{Address: "rri.example.org:51131"})
+	storage := &logical.InmemStorage{}
+
+	resp, err := b.handleReadConfig(context.Background(), &logical.Request{Storage: storage}, &framework.FieldData{
+		Raw:    map[string]interface{}{"env": "ote"},
+		Schema: configFields,
+	})
+	require.NoError(t, err)
+	assert.Nil(t, resp)
+}
+
+func TestHandleWriteConfigRequiresCredentials(t *testing.T) {
+	b := New(Config{Address: "rri.example.org:51131"})
+	storage := &logical.InmemStorage{}
+
+	_, err := b.handleWriteConfig(context.Background(), &logical.Request{Storage: storage}, &framework.FieldData{
+		Raw:    map[string]interface{}{"env": "ote", "user": "", "password": ""},
+		Schema: configFields,
+	})
+	assert.Error(t, err)
+}
+
+func TestHandleDeleteConfig(t *testing.T) {
+	b := New(Config{Address: "rri.example.org:51131"})
+	storage := &logical.InmemStorage{}
+	ctx := context.Background()
+
+	_, err := b.handleWriteConfig(ctx, &logical.Request{Storage: storage}, &framework.FieldData{
+		Raw:    map[string]interface{}{"env": "ote", "user": "u", "password": "p"},
+		Schema: configFields,
+	})
+	require.NoError(t, err)
+
+	_, err = b.handleDeleteConfig(ctx, &logical.Request{Storage: storage}, &framework.FieldData{
+		Raw:    map[string]interface{}{"env": "ote"},
+		Schema: configFields,
+	})
+	require.NoError(t, err)
+
+	resp, err := b.handleReadConfig(ctx, &logical.Request{Storage: storage}, &framework.FieldData{
+		Raw:    map[string]interface{}{"env": "ote"},
+		Schema: configFields,
+	})
+	require.NoError(t, err)
+	assert.Nil(t, resp)
+}
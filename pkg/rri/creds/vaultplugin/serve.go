@@ -0,0 +1,23 @@
+package vaultplugin
+
+import (
+	"context"
+
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/sdk/plugin"
+)
+
+// Serve runs the current process as a Vault plugin secrets engine, handing
+// out ephemeral RRI sessions against rriAddress. It blocks until Vault
+// terminates the plugin process.
+func Serve(rriAddress string) error {
+	return plugin.Serve(&plugin.ServeOpts{
+		BackendFactoryFunc: func(ctx context.Context, conf *logical.BackendConfig) (logical.Backend, error) {
+			b := New(Config{Address: rriAddress})
+			if err := b.Setup(ctx, conf); err != nil {
+				return nil, err
+			}
+			return b, nil
+		},
+	})
+}
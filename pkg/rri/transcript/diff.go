@@ -0,0 +1,59 @@
+package transcript
+
+import "fmt"
+
+// DiffTranscripts compares two previously recorded transcripts entry by
+// entry and returns a Diff for every position whose response changed.
+// Transcripts of different length are compared over the longer one's
+// length; trailing entries on the shorter side are reported as diffs
+// against a zero-value (empty) entry.
+func DiffTranscripts(a, b []Entry) []Diff {
+	var diffs []Diff
+
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+
+	for i := 0; i < n; i++ {
+		var ea, eb Entry
+		if i < len(a) {
+			ea = a[i]
+		}
+		if i < len(b) {
+			eb = b[i]
+		}
+
+		if ea.Successful != eb.Successful || ea.RawResponse != eb.RawResponse {
+			diffs = append(diffs, Diff{
+				Sequence:        i,
+				Query:           firstNonEmpty(ea.RawQuery, eb.RawQuery),
+				RecordedSuccess: ea.Successful,
+				ActualSuccess:   eb.Successful,
+				RecordedRaw:     ea.RawResponse,
+				ActualRaw:       eb.RawResponse,
+			})
+		}
+	}
+
+	return diffs
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if len(v) > 0 {
+			return v
+		}
+	}
+	return ""
+}
+
+// String renders a Diff as a human-readable block for CLI output, including
+// the full recorded and actual raw responses so a response-content change
+// isn't hidden behind an unchanged success flag.
+func (d Diff) String() string {
+	return fmt.Sprintf(
+		"#%d: success %t -> %t\n  query:    %s\n  recorded: %s\n  actual:   %s",
+		d.Sequence, d.RecordedSuccess, d.ActualSuccess, d.Query, d.RecordedRaw, d.ActualRaw,
+	)
+}
@@ -0,0 +1,54 @@
+package transcript
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffTranscriptsFindsChangedResponse(t *testing.T) {
+	a := []Entry{{Sequence: 0, RawQuery: "action: INFO", RawResponse: "result: success", Successful: true}}
+	b := []Entry{{Sequence: 0, RawQuery: "action: INFO", RawResponse: "result: success\nstatus: locked", Successful: true}}
+
+	diffs := DiffTranscripts(a, b)
+	assert.Len(t, diffs, 1)
+	assert.Equal(t, true, diffs[0].RecordedSuccess)
+	assert.Equal(t, true, diffs[0].ActualSuccess)
+}
+
+func TestDiffTranscriptsIdentical(t *testing.T) {
+	a := []Entry{{Sequence: 0, RawResponse: "result: success", Successful: true}}
+	b := []Entry{{Sequence: 0, RawResponse: "result: success", Successful: true}}
+
+	assert.Empty(t, DiffTranscripts(a, b))
+}
+
+func TestDiffTranscriptsReportsTrailingEntriesOnLongerSide(t *testing.T) {
+	a := []Entry{{Sequence: 0, RawQuery: "action: INFO", RawResponse: "result: success", Successful: true}}
+	b := []Entry{
+		{Sequence: 0, RawQuery: "action: INFO", RawResponse: "result: success", Successful: true},
+		{Sequence: 1, RawQuery: "action: CHECK", RawResponse: "result: success", Successful: true},
+	}
+
+	diffs := DiffTranscripts(a, b)
+	assert.Len(t, diffs, 1)
+	assert.Equal(t, 1, diffs[0].Sequence)
+	assert.Equal(t, false, diffs[0].RecordedSuccess)
+	assert.Equal(t, true, diffs[0].ActualSuccess)
+	assert.Equal(t, "", diffs[0].RecordedRaw)
+	assert.Equal(t, "result: success", diffs[0].ActualRaw)
+}
+
+func TestDiffStringIncludesRawResponsesEvenWhenSuccessUnchanged(t *testing.T) {
+	d := Diff{
+		Sequence:        0,
+		RecordedSuccess: true,
+		ActualSuccess:   true,
+		RecordedRaw:     "result: success\nstatus: active",
+		ActualRaw:       "result: success\nstatus: locked",
+	}
+
+	s := d.String()
+	assert.Contains(t, s, "status: active")
+	assert.Contains(t, s, "status: locked")
+}
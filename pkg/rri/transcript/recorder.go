@@ -0,0 +1,116 @@
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/DENICeG/go-rriclient/pkg/rri"
+)
+
+// Recorder writes a transcript of a session to a JSON-lines file, one Entry
+// per query/response pair it observes via Sent/Received. It is meant to be
+// hooked into rri.Client.RawQueryPrinter via RawQueryPrinter, upgrading the
+// existing verbose output to a --record foo.jsonl transcript. Every
+// RawQuery/RawResponse is passed through censor before it is written, the
+// same as the console's verbose output.
+type Recorder struct {
+	address string
+	censor  *rri.Censor
+	w       *bufio.Writer
+	closer  io.Closer
+	enc     *json.Encoder
+
+	sequence int
+	pending  *Entry
+}
+
+// NewRecorder creates or truncates path and returns a Recorder writing
+// transcript entries for a session against address. Every recorded message
+// is censored with censor before being written to disk; pass
+// rri.NewDefaultCensor() for the historical password-only behavior.
+func NewRecorder(path, address string, censor *rri.Censor) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create transcript %q: %w", path, err)
+	}
+
+	w := bufio.NewWriter(f)
+	return &Recorder{
+		address: address,
+		censor:  censor,
+		w:       w,
+		closer:  f,
+		enc:     json.NewEncoder(w),
+	}, nil
+}
+
+// Sent records that rawQuery was just sent.
+func (r *Recorder) Sent(rawQuery string) {
+	r.pending = &Entry{
+		Sequence: r.sequence,
+		Address:  r.address,
+		SentAt:   time.Now(),
+		RawQuery: r.censor.Apply(rawQuery),
+	}
+}
+
+// Received records rawResponse as the answer to the last query passed to
+// Sent and appends the completed Entry to the transcript file.
+func (r *Recorder) Received(rawResponse string, successful bool) error {
+	if r.pending == nil {
+		return fmt.Errorf("received a response with no matching sent query")
+	}
+
+	entry := *r.pending
+	entry.Duration = time.Since(entry.SentAt)
+	entry.RawResponse = r.censor.Apply(rawResponse)
+	entry.Successful = successful
+
+	r.pending = nil
+	r.sequence++
+
+	if err := r.enc.Encode(entry); err != nil {
+		return fmt.Errorf("write transcript entry: %w", err)
+	}
+
+	return nil
+}
+
+// Close flushes and closes the underlying transcript file.
+func (r *Recorder) Close() error {
+	if err := r.w.Flush(); err != nil {
+		return err
+	}
+	return r.closer.Close()
+}
+
+// RawQueryPrinter returns a rri.Client.RawQueryPrinter-compatible hook that
+// records every sent/received message pair, so existing --verbose output
+// can be upgraded to a transcript with --record foo.jsonl.
+func (r *Recorder) RawQueryPrinter() func(kind, message string) {
+	return func(kind, message string) {
+		switch kind {
+		case "sent":
+			r.Sent(message)
+		case "received":
+			_ = r.Received(message, isSuccessfulRawResponse(message))
+		}
+	}
+}
+
+// isSuccessfulRawResponse reports whether a raw RRI response's "result"
+// field indicates success.
+func isSuccessfulRawResponse(rawResponse string) bool {
+	for _, line := range strings.Split(rawResponse, "\n") {
+		const prefix = "result: "
+		if strings.HasPrefix(line, prefix) {
+			return strings.EqualFold(strings.TrimSpace(line[len(prefix):]), "success")
+		}
+	}
+	return false
+}
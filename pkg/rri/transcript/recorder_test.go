@@ -0,0 +1,30 @@
+package transcript
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DENICeG/go-rriclient/pkg/rri"
+)
+
+func TestRecorderCensorsWrittenEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+
+	r, err := NewRecorder(path, "rri.example.org:51131", rri.NewDefaultCensor())
+	require.NoError(t, err)
+
+	r.Sent("version: 3.0\naction: LOGIN\nuser: DENIC-1000011-RRI\npassword: super-secret")
+	require.NoError(t, r.Received("version: 3.0\nresult: success", true))
+	require.NoError(t, r.Close())
+
+	entries, err := Read(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	assert.NotContains(t, entries[0].RawQuery, "super-secret")
+	assert.Contains(t, entries[0].RawQuery, "password: ******")
+	assert.True(t, entries[0].Successful)
+}
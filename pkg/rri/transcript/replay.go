@@ -0,0 +1,131 @@
+package transcript
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DENICeG/go-rriclient/pkg/rri"
+)
+
+// ReplayOptions configures a Replay run.
+type ReplayOptions struct {
+	// Values substitutes "${name}" placeholders in recorded queries before
+	// replaying them, e.g. Values["domain"] for "${domain}".
+	Values map[string]string
+	// StopOnDiff halts replay as soon as a response's status or fields
+	// diverge from the recorded one.
+	StopOnDiff bool
+	// Censor is applied to the live response (and query, before logging it
+	// in a Diff) before comparing against the transcript, so that fields
+	// the recording censor redacted at record time don't show up as
+	// spurious diffs. It should match the censor NewRecorder was given when
+	// the transcript was recorded; pass rri.NewDefaultCensor() if unsure.
+	//
+	// Replay only works around censored fields that are output (part of the
+	// response or otherwise don't affect what gets sent), the same way it
+	// works around the password field of a LOGIN query by skipping LOGIN
+	// entries entirely. If a configured pattern also censors a field a
+	// non-LOGIN query relies on as input (e.g. authinfo on a transfer or
+	// update action), the recorded entry itself already stored the masked
+	// placeholder, so replaying it sends that placeholder to the server
+	// instead of the original value and will legitimately fail or diff on
+	// every run; there is no recovering the original value at replay time.
+	Censor *rri.Censor
+}
+
+// Diff describes one entry whose replayed response diverged from the one
+// recorded in the transcript.
+type Diff struct {
+	Sequence        int
+	Query           string
+	RecordedSuccess bool
+	ActualSuccess   bool
+	RecordedRaw     string
+	ActualRaw       string
+}
+
+// Replay resends every non-LOGIN query of a transcript against client and
+// compares each actual response to the one recorded, returning every Diff
+// found (or stopping at the first one when opts.StopOnDiff is set). Recorded
+// LOGIN entries are skipped: the caller is expected to log in separately
+// (runReplay does this via --user/--pass), and Recorder permanently censors
+// the password field of a recorded LOGIN query, so replaying it verbatim
+// would only fail authentication and surface as a spurious diff.
+//
+// The live response (and the query logged in a Diff) is passed through
+// opts.Censor before comparison, matching the censor applied to the
+// transcript at record time; otherwise any field it redacts would show up
+// as a spurious diff on every replay.
+func Replay(entries []Entry, client *rri.Client, opts ReplayOptions) ([]Diff, error) {
+	censor := opts.Censor
+	if censor == nil {
+		censor = rri.NewDefaultCensor()
+	}
+
+	var diffs []Diff
+
+	for _, entry := range entries {
+		if isLoginEntry(entry.RawQuery) {
+			continue
+		}
+
+		rawQuery := substituteValues(entry.RawQuery, opts.Values)
+
+		queries, err := rri.ParseQueries(rawQuery)
+		if err != nil {
+			return diffs, fmt.Errorf("entry %d: parse query: %w", entry.Sequence, err)
+		}
+		if len(queries) != 1 {
+			return diffs, fmt.Errorf("entry %d: expected exactly one query, got %d", entry.Sequence, len(queries))
+		}
+
+		response, err := client.SendQuery(queries[0])
+		if err != nil {
+			return diffs, fmt.Errorf("entry %d: send query: %w", entry.Sequence, err)
+		}
+
+		actualSuccess := response != nil && response.IsSuccessful()
+		actualRaw := ""
+		if response != nil {
+			actualRaw = censor.Apply(response.String())
+		}
+
+		if actualSuccess != entry.Successful || actualRaw != entry.RawResponse {
+			d := Diff{
+				Sequence:        entry.Sequence,
+				Query:           censor.Apply(rawQuery),
+				RecordedSuccess: entry.Successful,
+				ActualSuccess:   actualSuccess,
+				RecordedRaw:     entry.RawResponse,
+				ActualRaw:       actualRaw,
+			}
+			diffs = append(diffs, d)
+
+			if opts.StopOnDiff {
+				return diffs, nil
+			}
+		}
+	}
+
+	return diffs, nil
+}
+
+// substituteValues replaces every "${name}" placeholder in s with
+// values["name"], leaving unknown placeholders untouched.
+func substituteValues(s string, values map[string]string) string {
+	for name, value := range values {
+		s = strings.ReplaceAll(s, "${"+name+"}", value)
+	}
+	return s
+}
+
+// isLoginEntry reports whether a recorded raw query is a LOGIN request.
+func isLoginEntry(rawQuery string) bool {
+	for _, line := range strings.Split(rawQuery, "\n") {
+		const prefix = "action: "
+		if strings.HasPrefix(line, prefix) {
+			return strings.EqualFold(strings.TrimSpace(line[len(prefix):]), "LOGIN")
+		}
+	}
+	return false
+}
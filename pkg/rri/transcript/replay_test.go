@@ -0,0 +1,27 @@
+package transcript
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsLoginEntryDetectsLogin(t *testing.T) {
+	assert.True(t, isLoginEntry("version: 3.0\naction: LOGIN\nuser: DENIC-1000011-RRI\npassword: ******"))
+	assert.True(t, isLoginEntry("action: login\nuser: DENIC-1000011-RRI"))
+}
+
+func TestIsLoginEntryIgnoresOtherActions(t *testing.T) {
+	assert.False(t, isLoginEntry("version: 3.0\naction: INFO\ndomain: denic.de"))
+	assert.False(t, isLoginEntry(""))
+}
+
+func TestSubstituteValues(t *testing.T) {
+	s := substituteValues("action: INFO\ndomain: ${domain}", map[string]string{"domain": "denic.de"})
+	assert.Equal(t, "action: INFO\ndomain: denic.de", s)
+}
+
+func TestSubstituteValuesLeavesUnknownPlaceholders(t *testing.T) {
+	s := substituteValues("domain: ${domain}", nil)
+	assert.Equal(t, "domain: ${domain}", s)
+}
@@ -0,0 +1,29 @@
+// Package transcript records RRI sessions as a structured, replayable
+// JSON-lines file: one Entry per query/response pair, including timing,
+// the server address and censored credentials. Transcripts can later be
+// replayed against a fresh server or diffed against each other to see which
+// responses changed, which is useful for regression-testing a script
+// against DENIC's OT&E environment before promoting it to production.
+package transcript
+
+import (
+	"time"
+)
+
+// Entry is one recorded query/response pair of a transcript.
+type Entry struct {
+	// Sequence is the 0-based position of this entry within the session.
+	Sequence int `json:"sequence"`
+	// Address is the RRI host the session was talking to.
+	Address string `json:"address"`
+	// SentAt is when RawQuery was sent.
+	SentAt time.Time `json:"sentAt"`
+	// Duration is how long the server took to answer.
+	Duration time.Duration `json:"duration"`
+	// RawQuery is the censored raw message sent to the server.
+	RawQuery string `json:"rawQuery"`
+	// RawResponse is the censored raw message received from the server.
+	RawResponse string `json:"rawResponse"`
+	// Successful mirrors rri.Response.IsSuccessful() for RawResponse.
+	Successful bool `json:"successful"`
+}
@@ -0,0 +1,109 @@
+package rriservice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/DENICeG/go-rriclient/pkg/rri"
+	"github.com/DENICeG/go-rriclient/pkg/rriservice/rriservicepb"
+)
+
+// queryFor builds a raw RRI query from the given action and ordered
+// key/value field pairs, mirroring the "key: value" line format pkg/rri
+// already parses and emits.
+func queryFor(action string, fields ...[2]string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "version: 3.0\naction: %s\n", action)
+	for _, f := range fields {
+		fmt.Fprintf(&b, "%s: %s\n", f[0], f[1])
+	}
+	return b.String()
+}
+
+func (s *Server) sendRaw(sessionID, rawQuery string) (*rriservicepb.QueryResponse, error) {
+	envName, err := s.resolveEnvironment(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := s.acquire(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	queries, err := rri.ParseQueries(rawQuery)
+	if err != nil {
+		return nil, err
+	}
+	if len(queries) != 1 {
+		return nil, fmt.Errorf("expected exactly one query, got %d", len(queries))
+	}
+
+	return s.sendOne(envName, queries[0])
+}
+
+// DomainCheck implements rriservicepb.RRIServiceServer.
+func (s *Server) DomainCheck(ctx context.Context, req *rriservicepb.DomainCheckRequest) (*rriservicepb.DomainCheckResponse, error) {
+	resp, err := s.sendRaw(req.SessionId, queryFor("CHECK", [2]string{"domain", req.DomainName}))
+	if err != nil {
+		return nil, err
+	}
+	return &rriservicepb.DomainCheckResponse{Available: resp.Successful}, nil
+}
+
+// DomainInfo implements rriservicepb.RRIServiceServer.
+func (s *Server) DomainInfo(ctx context.Context, req *rriservicepb.DomainInfoRequest) (*rriservicepb.DomainInfoResponse, error) {
+	resp, err := s.sendRaw(req.SessionId, queryFor("INFO", [2]string{"domain", req.DomainName}))
+	if err != nil {
+		return nil, err
+	}
+	return &rriservicepb.DomainInfoResponse{RawResponse: resp.RawResponse}, nil
+}
+
+// DomainCreate implements rriservicepb.RRIServiceServer.
+func (s *Server) DomainCreate(ctx context.Context, req *rriservicepb.DomainCreateRequest) (*rriservicepb.DomainCreateResponse, error) {
+	fields := [][2]string{{"domain", req.DomainName}, {"holder", req.HolderHandle}}
+	for _, ns := range req.NameServers {
+		fields = append(fields, [2]string{"nserver", ns})
+	}
+
+	resp, err := s.sendRaw(req.SessionId, queryFor("CREATE", fields...))
+	if err != nil {
+		return nil, err
+	}
+	return &rriservicepb.DomainCreateResponse{Result: resp}, nil
+}
+
+// DomainUpdate implements rriservicepb.RRIServiceServer.
+func (s *Server) DomainUpdate(ctx context.Context, req *rriservicepb.DomainUpdateRequest) (*rriservicepb.DomainUpdateResponse, error) {
+	fields := [][2]string{{"domain", req.DomainName}}
+	for _, ns := range req.NameServers {
+		fields = append(fields, [2]string{"nserver", ns})
+	}
+
+	resp, err := s.sendRaw(req.SessionId, queryFor("UPDATE", fields...))
+	if err != nil {
+		return nil, err
+	}
+	return &rriservicepb.DomainUpdateResponse{Result: resp}, nil
+}
+
+// DomainDelete implements rriservicepb.RRIServiceServer.
+func (s *Server) DomainDelete(ctx context.Context, req *rriservicepb.DomainDeleteRequest) (*rriservicepb.DomainDeleteResponse, error) {
+	resp, err := s.sendRaw(req.SessionId, queryFor("DELETE", [2]string{"domain", req.DomainName}))
+	if err != nil {
+		return nil, err
+	}
+	return &rriservicepb.DomainDeleteResponse{Result: resp}, nil
+}
+
+// ChangeProvider implements rriservicepb.RRIServiceServer.
+func (s *Server) ChangeProvider(ctx context.Context, req *rriservicepb.ChangeProviderRequest) (*rriservicepb.ChangeProviderResponse, error) {
+	resp, err := s.sendRaw(req.SessionId, queryFor("CHPROV", [2]string{"domain", req.DomainName}, [2]string{"new-provider", req.NewProviderHandle}))
+	if err != nil {
+		return nil, err
+	}
+	return &rriservicepb.ChangeProviderResponse{Result: resp}, nil
+}
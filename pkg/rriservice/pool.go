@@ -0,0 +1,185 @@
+package rriservice
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/DENICeG/go-rriclient/pkg/rri"
+)
+
+// EnvironmentConfig describes one RRI environment the pool maintains
+// authenticated connections for.
+type EnvironmentConfig struct {
+	Name     string
+	Address  string
+	User     string
+	Password string
+	// PoolSize is the number of concurrent RRI connections kept open for
+	// this environment. Defaults to 1 when unset.
+	PoolSize int
+	// HealthCheckInterval is the period between no-op health check queries.
+	// Defaults to 30s when unset.
+	HealthCheckInterval time.Duration
+}
+
+// conn wraps a pooled rri.Client with the mutex that serializes access to it,
+// since a single RRI TCP session cannot interleave requests.
+type conn struct {
+	mu     sync.Mutex
+	client *rri.Client
+	config EnvironmentConfig
+}
+
+func (c *conn) ensureConnected() error {
+	if c.client != nil {
+		return nil
+	}
+
+	client, err := rri.NewClient(c.config.Address)
+	if err != nil {
+		return fmt.Errorf("connect to %q: %w", c.config.Address, err)
+	}
+
+	if err := client.Login(c.config.User, c.config.Password); err != nil {
+		client.Close()
+		return fmt.Errorf("login to %q as %q: %w", c.config.Address, c.config.User, err)
+	}
+
+	c.client = client
+	return nil
+}
+
+// healthCheckQuery is a cheap, side-effect-free RRI query used to verify a
+// pooled connection is still alive.
+const healthCheckQuery = "version: 3.0\naction: INFO\ndomain: denic.de"
+
+func (c *conn) healthCheck() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client == nil {
+		return
+	}
+
+	queries, err := rri.ParseQueries(healthCheckQuery)
+	if err != nil || len(queries) == 0 {
+		return
+	}
+
+	if _, err := c.client.SendQuery(queries[0]); err != nil {
+		c.client.Close()
+		c.client = nil
+	}
+}
+
+// withClient runs fn against a healthy, authenticated client for the pool
+// entry, reconnecting and re-logging in first if the previous connection
+// dropped.
+func (c *conn) withClient(fn func(*rri.Client) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.ensureConnected(); err != nil {
+		return err
+	}
+
+	if err := fn(c.client); err != nil {
+		// the underlying TCP session may have dropped; drop our handle so the
+		// next call reconnects and re-logs in rather than retrying the same
+		// possibly-dead connection.
+		c.client.Close()
+		c.client = nil
+		return err
+	}
+
+	return nil
+}
+
+// Pool multiplexes concurrent RPCs onto a small number of long-lived,
+// authenticated RRI connections per environment.
+type Pool struct {
+	mu    sync.Mutex
+	envs  map[string][]*conn
+	next  map[string]int
+	stopC chan struct{}
+}
+
+// NewPool builds a pool from the given environment configs and starts
+// periodic health checking for each.
+func NewPool(envs []EnvironmentConfig) *Pool {
+	p := &Pool{
+		envs:  make(map[string][]*conn),
+		next:  make(map[string]int),
+		stopC: make(chan struct{}),
+	}
+
+	for _, e := range envs {
+		if e.PoolSize <= 0 {
+			e.PoolSize = 1
+		}
+		conns := make([]*conn, e.PoolSize)
+		for i := range conns {
+			conns[i] = &conn{config: e}
+		}
+		p.envs[e.Name] = conns
+
+		interval := e.HealthCheckInterval
+		if interval <= 0 {
+			interval = 30 * time.Second
+		}
+		go p.runHealthChecks(e.Name, interval)
+	}
+
+	return p
+}
+
+func (p *Pool) runHealthChecks(envName string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, c := range p.envs[envName] {
+				c.healthCheck()
+			}
+		case <-p.stopC:
+			return
+		}
+	}
+}
+
+// Close stops health checking and closes every pooled connection.
+func (p *Pool) Close() {
+	close(p.stopC)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, conns := range p.envs {
+		for _, c := range conns {
+			c.mu.Lock()
+			if c.client != nil {
+				c.client.Close()
+				c.client = nil
+			}
+			c.mu.Unlock()
+		}
+	}
+}
+
+// Use runs fn against a pooled, authenticated client for envName, picking
+// connections round-robin across the environment's pool.
+func (p *Pool) Use(envName string, fn func(*rri.Client) error) error {
+	p.mu.Lock()
+	conns, ok := p.envs[envName]
+	if !ok {
+		p.mu.Unlock()
+		return fmt.Errorf("unknown environment %q", envName)
+	}
+	idx := p.next[envName] % len(conns)
+	p.next[envName]++
+	p.mu.Unlock()
+
+	return conns[idx].withClient(fn)
+}
@@ -0,0 +1,17 @@
+package rriservice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DENICeG/go-rriclient/pkg/rri"
+)
+
+func TestPoolUseUnknownEnvironment(t *testing.T) {
+	pool := NewPool(nil)
+	defer pool.Close()
+
+	err := pool.Use("does-not-exist", func(*rri.Client) error { return nil })
+	assert.Error(t, err)
+}
@@ -0,0 +1,5 @@
+// Package rriservicepb holds the generated protobuf and grpc-gateway stubs
+// for proto/rriservice.proto. The .pb.go and .pb.gw.go files are produced by
+// `make proto` and are not checked in; run that target before building
+// pkg/rriservice or cmd/rri-server.
+package rriservicepb
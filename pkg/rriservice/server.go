@@ -0,0 +1,193 @@
+// Package rriservice wraps pkg/rri.Client behind a gRPC (and, via
+// grpc-gateway, REST) API so a fleet of internal tools can share one
+// long-lived, authenticated RRI session per environment instead of each
+// spawning the CLI.
+package rriservice
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sync/semaphore"
+
+	"github.com/DENICeG/go-rriclient/pkg/rri"
+	"github.com/DENICeG/go-rriclient/pkg/rriservice/rriservicepb"
+)
+
+// DefaultMaxConcurrentQueriesPerCaller bounds how many in-flight queries a
+// single session may have against the shared pool at once.
+const DefaultMaxConcurrentQueriesPerCaller = 4
+
+// Server implements rriservicepb.RRIServiceServer on top of a Pool of
+// authenticated RRI connections.
+type Server struct {
+	rriservicepb.UnimplementedRRIServiceServer
+
+	pool *Pool
+
+	mu                     sync.Mutex
+	sessions               map[string]string // session id -> environment name
+	maxConcurrentPerCaller int64
+	callerLimits           map[string]*semaphore.Weighted
+}
+
+// NewServer builds a Server multiplexing RPCs onto pool.
+func NewServer(pool *Pool, maxConcurrentPerCaller int64) *Server {
+	if maxConcurrentPerCaller <= 0 {
+		maxConcurrentPerCaller = DefaultMaxConcurrentQueriesPerCaller
+	}
+
+	return &Server{
+		pool:                   pool,
+		sessions:               make(map[string]string),
+		maxConcurrentPerCaller: maxConcurrentPerCaller,
+		callerLimits:           make(map[string]*semaphore.Weighted),
+	}
+}
+
+func newSessionID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func (s *Server) resolveEnvironment(sessionID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	envName, ok := s.sessions[sessionID]
+	if !ok {
+		return "", fmt.Errorf("unknown or expired session %q", sessionID)
+	}
+	return envName, nil
+}
+
+func (s *Server) acquire(sessionID string) (func(), error) {
+	s.mu.Lock()
+	limit, ok := s.callerLimits[sessionID]
+	if !ok {
+		limit = semaphore.NewWeighted(s.maxConcurrentPerCaller)
+		s.callerLimits[sessionID] = limit
+	}
+	s.mu.Unlock()
+
+	if err := limit.Acquire(context.Background(), 1); err != nil {
+		return nil, err
+	}
+	return func() { limit.Release(1) }, nil
+}
+
+// Login implements rriservicepb.RRIServiceServer. It does not perform a new
+// RRI LOGIN; the pool already holds an authenticated connection per
+// configured environment. Login instead hands the caller an opaque session
+// id scoping its concurrency limit and subsequent RPCs to that environment.
+func (s *Server) Login(ctx context.Context, req *rriservicepb.LoginRequest) (*rriservicepb.LoginResponse, error) {
+	sessionID, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.sessions[sessionID] = req.Environment
+	s.mu.Unlock()
+
+	return &rriservicepb.LoginResponse{SessionId: sessionID}, nil
+}
+
+// Logout implements rriservicepb.RRIServiceServer.
+func (s *Server) Logout(ctx context.Context, req *rriservicepb.LogoutRequest) (*rriservicepb.LogoutResponse, error) {
+	s.mu.Lock()
+	delete(s.sessions, req.SessionId)
+	delete(s.callerLimits, req.SessionId)
+	s.mu.Unlock()
+
+	return &rriservicepb.LogoutResponse{}, nil
+}
+
+// SendQuery implements rriservicepb.RRIServiceServer.
+func (s *Server) SendQuery(ctx context.Context, req *rriservicepb.QueryRequest) (*rriservicepb.QueryResponse, error) {
+	envName, err := s.resolveEnvironment(req.SessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := s.acquire(req.SessionId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	queries, err := rri.ParseQueries(req.RawQuery)
+	if err != nil {
+		return nil, err
+	}
+	if len(queries) != 1 {
+		return nil, fmt.Errorf("expected exactly one query, got %d", len(queries))
+	}
+
+	return s.sendOne(envName, queries[0])
+}
+
+func (s *Server) sendOne(envName string, query rri.Query) (*rriservicepb.QueryResponse, error) {
+	var resp *rriservicepb.QueryResponse
+
+	err := s.pool.Use(envName, func(client *rri.Client) error {
+		response, err := client.SendQuery(query)
+		if err != nil {
+			return err
+		}
+
+		resp = &rriservicepb.QueryResponse{}
+		if response != nil {
+			resp.RawResponse = rri.CensorRawMessage(response.String())
+			resp.Successful = response.IsSuccessful()
+			if !resp.Successful {
+				resp.ErrorMessage = response.ErrorMsg()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// ExecuteBatch implements rriservicepb.RRIServiceServer, parsing the same
+// '=-=' separated query format rri.ParseQueries accepts from the
+// file-driven CLI flow today and streaming back one response per query.
+func (s *Server) ExecuteBatch(req *rriservicepb.BatchQueryRequest, stream rriservicepb.RRIService_ExecuteBatchServer) error {
+	envName, err := s.resolveEnvironment(req.SessionId)
+	if err != nil {
+		return err
+	}
+
+	release, err := s.acquire(req.SessionId)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	queries, err := rri.ParseQueries(req.RawQuery)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range queries {
+		resp, err := s.sendOne(envName, query)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
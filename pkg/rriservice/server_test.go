@@ -0,0 +1,25 @@
+package rriservice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSessionIDIsUniqueAndHex(t *testing.T) {
+	a, err := newSessionID()
+	require.NoError(t, err)
+	b, err := newSessionID()
+	require.NoError(t, err)
+
+	assert.NotEqual(t, a, b)
+	assert.Len(t, a, 32)
+}
+
+func TestServerSendQueryUnknownSession(t *testing.T) {
+	s := NewServer(NewPool(nil), 0)
+
+	_, err := s.resolveEnvironment("no-such-session")
+	assert.Error(t, err)
+}